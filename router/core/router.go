@@ -24,6 +24,7 @@ import (
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
 	"github.com/wundergraph/cosmo/router/gen/proto/wg/cosmo/graphqlmetrics/v1/graphqlmetricsv1connect"
 	nodev1 "github.com/wundergraph/cosmo/router/gen/proto/wg/cosmo/node/v1"
@@ -47,6 +48,7 @@ import (
 	"github.com/wundergraph/cosmo/router/pkg/cors"
 	"github.com/wundergraph/cosmo/router/pkg/execution_config"
 	"github.com/wundergraph/cosmo/router/pkg/health"
+	"github.com/wundergraph/cosmo/router/pkg/logging"
 	"github.com/wundergraph/cosmo/router/pkg/mcpserver"
 	rmetric "github.com/wundergraph/cosmo/router/pkg/metric"
 	"github.com/wundergraph/cosmo/router/pkg/otel/otelconfig"
@@ -940,12 +942,46 @@ func (r *Router) bootstrap(ctx context.Context) error {
 			mcpserver.WithEnableArbitraryOperations(r.mcp.EnableArbitraryOperations),
 			mcpserver.WithExposeSchema(r.mcp.ExposeSchema),
 			mcpserver.WithStateless(r.mcp.Session.Stateless),
+			mcpserver.WithToolRateLimit(mcpserver.ToolRateLimitOptions{
+				Enabled:            r.mcp.RateLimit.Enabled,
+				Requests:           r.mcp.RateLimit.Requests,
+				Window:             r.mcp.RateLimit.Window,
+				Quota:              r.mcp.RateLimit.Quota,
+				SessionIdleTimeout: r.mcp.RateLimit.SessionIdleTimeout,
+				MaxSessions:        r.mcp.RateLimit.MaxSessions,
+			}),
+			mcpserver.WithHeaderForwarding(mcpserver.HeaderForwardingOptions{
+				AllowList: r.mcp.ForwardHeaders.AllowList,
+				DenyList:  r.mcp.ForwardHeaders.DenyList,
+			}),
 		}
 
 		if r.corsOptions != nil {
 			mcpOpts = append(mcpOpts, mcpserver.WithCORS(*r.corsOptions))
 		}
 
+		if r.mcp.AuditLogs.Enabled {
+			var auditLogWriter zapcore.WriteSyncer
+			if r.mcp.AuditLogs.Output.File.Enabled {
+				f, err := logging.NewLogFile(r.mcp.AuditLogs.Output.File.Path, os.FileMode(r.mcp.AuditLogs.Output.File.Mode))
+				if err != nil {
+					return fmt.Errorf("could not create mcp audit log file: %w", err)
+				}
+				// The file is closed together with the MCP server
+				r.mcpAuditLogFile = f
+				auditLogWriter = f
+			} else if r.mcp.AuditLogs.Output.Stdout.Enabled {
+				auditLogWriter = os.Stdout
+			}
+
+			if auditLogWriter != nil {
+				auditLogger := logging.NewZapAccessLogger(auditLogWriter, zapcore.InfoLevel, r.developmentMode, !r.jsonLog, false)
+				mcpOpts = append(mcpOpts, mcpserver.WithAuditLogger(auditLogger))
+			} else {
+				r.logger.Warn("MCP audit logs are enabled, but neither the stdout nor the file output is enabled. No audit logs will be written.")
+			}
+		}
+
 		// Determine the router GraphQL endpoint
 		var routerGraphQLEndpoint string
 
@@ -961,11 +997,13 @@ func (r *Router) bootstrap(ctx context.Context) error {
 			mcpOpts...,
 		)
 		if err != nil {
+			r.closeMCPAuditLogFile()
 			return fmt.Errorf("failed to create mcp server: %w", err)
 		}
 
 		err = mcpss.Start()
 		if err != nil {
+			r.closeMCPAuditLogFile()
 			return fmt.Errorf("failed to start MCP server: %w", err)
 		}
 
@@ -1440,6 +1478,18 @@ func (e *concSafeErrorJoiner) ErrOrNil() error {
 	return errors.Join(e.errs...)
 }
 
+// closeMCPAuditLogFile closes the audit log file of the MCP server, if any
+func (r *Router) closeMCPAuditLogFile() {
+	if r.mcpAuditLogFile == nil {
+		return
+	}
+
+	if err := r.mcpAuditLogFile.Close(); err != nil {
+		r.logger.Error("Failed to close MCP audit log file", zap.Error(err))
+	}
+	r.mcpAuditLogFile = nil
+}
+
 // Shutdown gracefully shuts down the router. It blocks until the server is shutdown.
 // If the router is already shutdown, the method returns immediately without error.
 func (r *Router) Shutdown(ctx context.Context) error {
@@ -1488,6 +1538,7 @@ func (r *Router) Shutdown(ctx context.Context) error {
 			if subErr := r.mcpServer.Stop(ctx); subErr != nil {
 				err.Append(fmt.Errorf("failed to shutdown mcp server: %w", subErr))
 			}
+			r.closeMCPAuditLogFile()
 		}()
 	}
 
@@ -2028,6 +2079,13 @@ func WithDevelopmentMode(enabled bool) Option {
 	}
 }
 
+// WithJSONLog sets whether logs created by the router, e.g. the MCP audit logs, are encoded as JSON instead of pretty printed.
+func WithJSONLog(enabled bool) Option {
+	return func(r *Router) {
+		r.jsonLog = enabled
+	}
+}
+
 func WithClusterName(name string) Option {
 	return func(r *Router) {
 		r.clusterName = name
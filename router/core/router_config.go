@@ -3,6 +3,7 @@ package core
 import (
 	"crypto/tls"
 	"net/http"
+	"os"
 	"time"
 
 	nodev1 "github.com/wundergraph/cosmo/router/gen/proto/wg/cosmo/node/v1"
@@ -107,8 +108,10 @@ type Config struct {
 	retryOptions                    retrytransport.RetryOptions
 	redisClient                     rd.RDCloser
 	mcpServer                       *mcpserver.GraphQLSchemaServer
+	mcpAuditLogFile                 *os.File
 	processStartTime                time.Time
 	developmentMode                 bool
+	jsonLog                         bool
 	healthcheck                     health.Checker
 	accessLogsConfig                *AccessLogsConfig
 	// If connecting to localhost inside Docker fails, fallback to the docker internal address for the host
@@ -252,6 +252,7 @@ func optionsFromResources(logger *zap.Logger, config *config.Config) []Option {
 			},
 		}),
 		WithDevelopmentMode(config.DevelopmentMode),
+		WithJSONLog(config.JSONLog),
 		WithTracing(TraceConfigFromTelemetry(&config.Telemetry)),
 		WithMetrics(MetricConfigFromTelemetry(&config.Telemetry)),
 		WithTelemetryAttributes(config.Telemetry.Attributes),
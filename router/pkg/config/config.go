@@ -989,21 +989,58 @@ type CacheWarmupConfiguration struct {
 }
 
 type MCPConfiguration struct {
-	Enabled                   bool             `yaml:"enabled" envDefault:"false" env:"MCP_ENABLED"`
-	Server                    MCPServer        `yaml:"server,omitempty"`
-	Storage                   MCPStorageConfig `yaml:"storage,omitempty"`
-	Session                   MCPSessionConfig `yaml:"session,omitempty"`
-	GraphName                 string           `yaml:"graph_name" envDefault:"mygraph" env:"MCP_GRAPH_NAME"`
-	ExcludeMutations          bool             `yaml:"exclude_mutations" envDefault:"false" env:"MCP_EXCLUDE_MUTATIONS"`
-	EnableArbitraryOperations bool             `yaml:"enable_arbitrary_operations" envDefault:"false" env:"MCP_ENABLE_ARBITRARY_OPERATIONS"`
-	ExposeSchema              bool             `yaml:"expose_schema" envDefault:"false" env:"MCP_EXPOSE_SCHEMA"`
-	RouterURL                 string           `yaml:"router_url,omitempty" env:"MCP_ROUTER_URL"`
+	Enabled                   bool                    `yaml:"enabled" envDefault:"false" env:"MCP_ENABLED"`
+	Server                    MCPServer               `yaml:"server,omitempty"`
+	Storage                   MCPStorageConfig        `yaml:"storage,omitempty"`
+	Session                   MCPSessionConfig        `yaml:"session,omitempty"`
+	RateLimit                 MCPRateLimitConfig      `yaml:"rate_limit,omitempty"`
+	AuditLogs                 MCPAuditLogsConfig      `yaml:"audit_logs,omitempty"`
+	ForwardHeaders            MCPForwardHeadersConfig `yaml:"forward_headers,omitempty"`
+	GraphName                 string                  `yaml:"graph_name" envDefault:"mygraph" env:"MCP_GRAPH_NAME"`
+	ExcludeMutations          bool                    `yaml:"exclude_mutations" envDefault:"false" env:"MCP_EXCLUDE_MUTATIONS"`
+	EnableArbitraryOperations bool                    `yaml:"enable_arbitrary_operations" envDefault:"false" env:"MCP_ENABLE_ARBITRARY_OPERATIONS"`
+	ExposeSchema              bool                    `yaml:"expose_schema" envDefault:"false" env:"MCP_EXPOSE_SCHEMA"`
+	RouterURL                 string                  `yaml:"router_url,omitempty" env:"MCP_ROUTER_URL"`
 }
 
 type MCPSessionConfig struct {
 	Stateless bool `yaml:"stateless" envDefault:"true" env:"MCP_SESSION_STATELESS"`
 }
 
+type MCPRateLimitConfig struct {
+	Enabled            bool          `yaml:"enabled" envDefault:"false" env:"MCP_RATE_LIMIT_ENABLED"`
+	Requests           int           `yaml:"requests" envDefault:"60" env:"MCP_RATE_LIMIT_REQUESTS"`
+	Window             time.Duration `yaml:"window" envDefault:"1m" env:"MCP_RATE_LIMIT_WINDOW"`
+	Quota              int           `yaml:"quota" envDefault:"0" env:"MCP_RATE_LIMIT_QUOTA"`
+	SessionIdleTimeout time.Duration `yaml:"session_idle_timeout" envDefault:"30m" env:"MCP_RATE_LIMIT_SESSION_IDLE_TIMEOUT"`
+	MaxSessions        int           `yaml:"max_sessions" envDefault:"10000" env:"MCP_RATE_LIMIT_MAX_SESSIONS"`
+}
+
+type MCPAuditLogsConfig struct {
+	Enabled bool                     `yaml:"enabled" envDefault:"false" env:"MCP_AUDIT_LOGS_ENABLED"`
+	Output  MCPAuditLogsOutputConfig `yaml:"output,omitempty"`
+}
+
+type MCPAuditLogsOutputConfig struct {
+	Stdout MCPAuditLogsStdOutOutputConfig `yaml:"stdout"`
+	File   MCPAuditLogsFileOutputConfig   `yaml:"file,omitempty"`
+}
+
+type MCPAuditLogsStdOutOutputConfig struct {
+	Enabled bool `yaml:"enabled" envDefault:"true" env:"MCP_AUDIT_LOGS_OUTPUT_STDOUT_ENABLED"`
+}
+
+type MCPAuditLogsFileOutputConfig struct {
+	Enabled bool     `yaml:"enabled" envDefault:"false" env:"MCP_AUDIT_LOGS_OUTPUT_FILE_ENABLED"`
+	Path    string   `yaml:"path" envDefault:"mcp_audit.log" env:"MCP_AUDIT_LOGS_OUTPUT_FILE_PATH"`
+	Mode    FileMode `yaml:"mode" envDefault:"0640" env:"MCP_AUDIT_LOGS_OUTPUT_FILE_MODE"`
+}
+
+type MCPForwardHeadersConfig struct {
+	AllowList []string `yaml:"allow_list,omitempty" env:"MCP_FORWARD_HEADERS_ALLOW_LIST"`
+	DenyList  []string `yaml:"deny_list,omitempty" env:"MCP_FORWARD_HEADERS_DENY_LIST"`
+}
+
 type MCPStorageConfig struct {
 	ProviderID string `yaml:"provider_id,omitempty" env:"MCP_STORAGE_PROVIDER_ID"`
 }
@@ -0,0 +1,61 @@
+package mcpserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// auditMiddleware returns a tool handler middleware recording every tool invocation to the audit logger.
+// The arguments are only recorded as a hash to keep sensitive input out of the audit trail.
+func auditMiddleware(auditLogger *zap.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			start := time.Now()
+
+			result, err := next(ctx, request)
+
+			status := "success"
+			if err != nil || (result != nil && result.IsError) {
+				status = "error"
+			}
+
+			fields := []zap.Field{
+				zap.String("tool", request.Params.Name),
+				zap.String("arguments_hash", hashArguments(request.GetArguments())),
+				zap.Duration("duration", time.Since(start)),
+				zap.String("status", status),
+			}
+
+			if session := server.ClientSessionFromContext(ctx); session != nil && session.SessionID() != "" {
+				fields = append(fields, zap.String("session_id", session.SessionID()))
+			}
+
+			if err != nil {
+				fields = append(fields, zap.Error(err))
+			}
+
+			auditLogger.Info("MCP tool invocation", fields...)
+
+			return result, err
+		}
+	}
+}
+
+// hashArguments returns the hex encoded SHA-256 hash of the JSON encoded tool arguments
+func hashArguments(arguments map[string]any) string {
+	// Map keys are sorted when encoding, so equal arguments always produce the same hash
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,94 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAuditMiddleware(t *testing.T) {
+	newRequest := func(arguments map[string]any) mcp.CallToolRequest {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "execute_operation_my_query"
+		request.Params.Arguments = arguments
+		return request
+	}
+
+	t.Run("records successful invocations", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		handler := auditMiddleware(zap.New(core))(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		})
+
+		_, err := handler(context.Background(), newRequest(map[string]any{"id": 1, "name": "secret"}))
+		require.NoError(t, err)
+
+		require.Equal(t, 1, logs.Len())
+		fields := logs.All()[0].ContextMap()
+		assert.Equal(t, "execute_operation_my_query", fields["tool"])
+		assert.Equal(t, "success", fields["status"])
+		assert.Contains(t, fields, "duration")
+		assert.Equal(t, hashArguments(map[string]any{"name": "secret", "id": 1}), fields["arguments_hash"])
+		assert.NotContains(t, fields["arguments_hash"], "secret")
+	})
+
+	t.Run("records failed invocations", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		handler := auditMiddleware(zap.New(core))(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultError("rejected"), nil
+		})
+
+		_, err := handler(context.Background(), newRequest(nil))
+		require.NoError(t, err)
+
+		require.Equal(t, 1, logs.Len())
+		assert.Equal(t, "error", logs.All()[0].ContextMap()["status"])
+
+		handler = auditMiddleware(zap.New(core))(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return nil, errors.New("failed to send request")
+		})
+
+		_, err = handler(context.Background(), newRequest(nil))
+		require.Error(t, err)
+
+		require.Equal(t, 2, logs.Len())
+		fields := logs.All()[1].ContextMap()
+		assert.Equal(t, "error", fields["status"])
+		assert.Equal(t, "failed to send request", fields["error"])
+	})
+}
+
+func TestAuditLogsPanickingTool(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	s, err := NewGraphQLSchemaServer("http://localhost:3002/graphql", WithAuditLogger(zap.New(core)))
+	require.NoError(t, err)
+
+	s.server.AddTool(mcp.NewTool("boom"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		panic("boom")
+	})
+
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "boom"},
+	})
+	require.NoError(t, err)
+
+	// The panic is recovered and returned as an error
+	_, ok := s.server.HandleMessage(context.Background(), message).(mcp.JSONRPCError)
+	require.True(t, ok)
+
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, "boom", fields["tool"])
+	assert.Equal(t, "error", fields["status"])
+	assert.Contains(t, fields["error"], "panic recovered in boom tool handler")
+}
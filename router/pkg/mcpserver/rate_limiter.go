@@ -0,0 +1,150 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+var (
+	errToolRateLimitExceeded = errors.New("tool invocation rate limit exceeded")
+	errToolQuotaExceeded     = errors.New("tool invocation quota exceeded")
+)
+
+// ToolRateLimitOptions configures the rate limiting of tool invocations per session
+type ToolRateLimitOptions struct {
+	// Enabled determines whether tool invocations are rate limited
+	Enabled bool
+	// Requests is the maximum number of tool invocations per session within the window
+	Requests int
+	// Window is the duration of the sliding window
+	Window time.Duration
+	// Quota is the maximum number of tool invocations over the lifetime of a session, 0 means unlimited.
+	// A client can start a new session at any time, so the quota is not a protection against abuse.
+	Quota int
+	// SessionIdleTimeout is the duration after which a session without any activity is dropped
+	SessionIdleTimeout time.Duration
+	// MaxSessions is the maximum number of sessions, new sessions are rejected once it is reached
+	MaxSessions int
+}
+
+// toolRateLimiter tracks tool invocations per session in a sliding window.
+// In stateless mode there is no session, so all invocations share the same limits.
+type toolRateLimiter struct {
+	mu        sync.Mutex
+	opts      ToolRateLimitOptions
+	stateless bool
+	sessions  map[string]*sessionUsage
+	now       func() time.Time
+}
+
+// sessionUsage holds the tool invocations of a single session
+type sessionUsage struct {
+	// invocations are the timestamps of the invocations within the current window, oldest first
+	invocations []time.Time
+	// total is the number of invocations over the lifetime of the session
+	total int
+}
+
+// newToolRateLimiter creates a new toolRateLimiter with the given options
+func newToolRateLimiter(opts ToolRateLimitOptions, stateless bool) (*toolRateLimiter, error) {
+	if opts.Requests <= 0 {
+		return nil, fmt.Errorf("rate limit requests must be greater than 0, got %d", opts.Requests)
+	}
+	if opts.Window <= 0 {
+		return nil, fmt.Errorf("rate limit window must be greater than 0, got %s", opts.Window)
+	}
+	if opts.Quota < 0 {
+		return nil, fmt.Errorf("rate limit quota must not be negative, got %d", opts.Quota)
+	}
+	// Without sessions, all clients share the same limits, so a quota would lock out every client once exhausted
+	if stateless && opts.Quota > 0 {
+		return nil, errors.New("rate limit quota requires stateful sessions")
+	}
+
+	return &toolRateLimiter{
+		opts:      opts,
+		stateless: stateless,
+		sessions:  make(map[string]*sessionUsage),
+		now:       time.Now,
+	}, nil
+}
+
+// take records a tool invocation of the session. If the invocation is not allowed, an error is returned
+// together with the duration after which the session may retry. A zero duration means retrying won't help.
+// The session must have been validated by the sessionManager.
+func (l *toolRateLimiter) take(sessionID string) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	usage, ok := l.sessions[sessionID]
+	if !ok {
+		usage = &sessionUsage{}
+		l.sessions[sessionID] = usage
+	}
+
+	if l.opts.Quota > 0 && usage.total >= l.opts.Quota {
+		return 0, errToolQuotaExceeded
+	}
+
+	// Drop the invocations which are no longer part of the sliding window
+	now := l.now()
+	windowStart := now.Add(-l.opts.Window)
+	expired := 0
+	for expired < len(usage.invocations) && !usage.invocations[expired].After(windowStart) {
+		expired++
+	}
+	usage.invocations = usage.invocations[expired:]
+
+	if len(usage.invocations) >= l.opts.Requests {
+		// The session may retry as soon as the oldest invocation leaves the window
+		return usage.invocations[0].Add(l.opts.Window).Sub(now), errToolRateLimitExceeded
+	}
+
+	usage.invocations = append(usage.invocations, now)
+	usage.total++
+
+	return 0, nil
+}
+
+// removeSession drops the tracked invocations of a session
+func (l *toolRateLimiter) removeSession(sessionID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.sessions, sessionID)
+}
+
+// middleware returns a tool handler middleware rejecting invocations exceeding the limits with a tool error
+func (l *toolRateLimiter) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// In stateless mode, the session ID sent by the client is neither issued nor validated by the server
+		var sessionID string
+		if session := server.ClientSessionFromContext(ctx); session != nil && !l.stateless {
+			sessionID = session.SessionID()
+		}
+
+		retryAfter, err := l.take(sessionID)
+		if err == nil {
+			return next(ctx, request)
+		}
+
+		if retryAfter <= 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("Tool %s rejected: %s.", request.Params.Name, err)), nil
+		}
+
+		retryAfterSeconds := int(math.Ceil(retryAfter.Seconds()))
+		result := mcp.NewToolResultError(fmt.Sprintf("Tool %s rejected: %s. Retry after %d seconds.", request.Params.Name, err, retryAfterSeconds))
+		result.Meta = map[string]any{
+			"retryAfter": retryAfterSeconds,
+		}
+
+		return result, nil
+	}
+}
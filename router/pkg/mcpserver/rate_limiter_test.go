@@ -0,0 +1,280 @@
+package mcpserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolRateLimiter(t *testing.T) {
+	t.Run("rejects invocations exceeding the sliding window", func(t *testing.T) {
+		limiter, err := newToolRateLimiter(ToolRateLimitOptions{Enabled: true, Requests: 2, Window: time.Minute}, false)
+		require.NoError(t, err)
+
+		now := time.Now()
+		limiter.now = func() time.Time { return now }
+
+		_, err = limiter.take("session")
+		require.NoError(t, err)
+
+		now = now.Add(20 * time.Second)
+		_, err = limiter.take("session")
+		require.NoError(t, err)
+
+		now = now.Add(20 * time.Second)
+		retryAfter, err := limiter.take("session")
+		require.ErrorIs(t, err, errToolRateLimitExceeded)
+		assert.Equal(t, 20*time.Second, retryAfter)
+
+		// Other sessions are tracked independently
+		_, err = limiter.take("other-session")
+		require.NoError(t, err)
+
+		// The oldest invocation left the window
+		now = now.Add(20 * time.Second)
+		_, err = limiter.take("session")
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects invocations exceeding the session quota", func(t *testing.T) {
+		limiter, err := newToolRateLimiter(ToolRateLimitOptions{Enabled: true, Requests: 10, Window: time.Second, Quota: 2}, false)
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			_, err = limiter.take("session")
+			require.NoError(t, err)
+		}
+
+		retryAfter, err := limiter.take("session")
+		require.ErrorIs(t, err, errToolQuotaExceeded)
+		assert.Zero(t, retryAfter)
+
+		// The quota is released together with the session
+		limiter.removeSession("session")
+		assert.Empty(t, limiter.sessions)
+	})
+
+	t.Run("returns a tool error with a retry hint", func(t *testing.T) {
+		limiter, err := newToolRateLimiter(ToolRateLimitOptions{Enabled: true, Requests: 1, Window: 30 * time.Second}, true)
+		require.NoError(t, err)
+
+		handler := limiter.middleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		})
+
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "execute_operation_my_query"
+
+		result, err := handler(context.Background(), request)
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+
+		result, err = handler(context.Background(), request)
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, 30, result.Meta["retryAfter"])
+		require.Len(t, result.Content, 1)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Retry after 30 seconds")
+	})
+
+	t.Run("rejects invalid options", func(t *testing.T) {
+		_, err := newToolRateLimiter(ToolRateLimitOptions{Enabled: true, Requests: 0, Window: time.Second}, false)
+		require.Error(t, err)
+
+		_, err = newToolRateLimiter(ToolRateLimitOptions{Enabled: true, Requests: 1}, false)
+		require.Error(t, err)
+
+		_, err = newToolRateLimiter(ToolRateLimitOptions{Enabled: true, Requests: 1, Window: time.Second, Quota: -1}, false)
+		require.Error(t, err)
+
+		// All clients share the same limits in stateless mode, a quota would lock them out
+		_, err = newToolRateLimiter(ToolRateLimitOptions{Enabled: true, Requests: 1, Window: time.Second, Quota: 1}, true)
+		require.Error(t, err)
+	})
+}
+
+// newTestMCPServer serves the streamable HTTP transport of the MCP server
+func newTestMCPServer(t *testing.T, s *GraphQLSchemaServer) *httptest.Server {
+	t.Helper()
+
+	mcpServer := httptest.NewServer(s.limitSessions(s.newStreamableHTTPServer(nil)))
+	t.Cleanup(mcpServer.Close)
+
+	return mcpServer
+}
+
+// sendMCPRequest sends the JSON-RPC message to the MCP server, with the session ID if not empty
+func sendMCPRequest(t *testing.T, url, method, sessionID string, message map[string]any) *http.Response {
+	t.Helper()
+
+	body, err := json.Marshal(message)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if sessionID != "" {
+		req.Header.Set(server.HeaderKeySessionID, sessionID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	return resp
+}
+
+// initializeMCPSession sends an initialize request to the MCP server
+func initializeMCPSession(t *testing.T, url string) *http.Response {
+	t.Helper()
+
+	return sendMCPRequest(t, url, http.MethodPost, "", map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      1,
+		"method":  "initialize",
+		"params": map[string]any{
+			"protocolVersion": mcp.LATEST_PROTOCOL_VERSION,
+			"clientInfo":      map[string]any{"name": "test", "version": "1.0.0"},
+		},
+	})
+}
+
+// callMCPTool calls the get_schema tool and returns the status code and, if successful, the tool result
+func callMCPTool(t *testing.T, url, sessionID string) (int, *mcp.CallToolResult) {
+	t.Helper()
+
+	resp := sendMCPRequest(t, url, http.MethodPost, sessionID, map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      2,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "get_schema"},
+	})
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+
+	var response struct {
+		Result mcp.CallToolResult `json:"result"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+
+	return resp.StatusCode, &response.Result
+}
+
+func TestToolRateLimitStreamableHTTP(t *testing.T) {
+	t.Run("limits issued sessions", func(t *testing.T) {
+		s, err := NewGraphQLSchemaServer("http://localhost:3002/graphql",
+			WithOperationsDir(""),
+			WithStateless(false),
+			WithToolRateLimit(ToolRateLimitOptions{Enabled: true, Requests: 10, Window: time.Minute, Quota: 1}),
+		)
+		require.NoError(t, err)
+		require.NoError(t, s.Reload(newTestSchema(t)))
+
+		mcpServer := newTestMCPServer(t, s)
+
+		resp := initializeMCPSession(t, mcpServer.URL)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		sessionID := resp.Header.Get(server.HeaderKeySessionID)
+		require.NotEmpty(t, sessionID)
+
+		status, result := callMCPTool(t, mcpServer.URL, sessionID)
+		require.Equal(t, http.StatusOK, status)
+		assert.False(t, result.IsError)
+
+		status, result = callMCPTool(t, mcpServer.URL, sessionID)
+		require.Equal(t, http.StatusOK, status)
+		assert.True(t, result.IsError)
+		require.Len(t, result.Content, 1)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, errToolQuotaExceeded.Error())
+
+		// A made-up session ID doesn't get a fresh quota and isn't tracked
+		status, _ = callMCPTool(t, mcpServer.URL, sessionIDPrefix+uuid.NewString())
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.Len(t, s.sessionManager.sessions, 1)
+
+		// A terminated session is rejected
+		resp = sendMCPRequest(t, mcpServer.URL, http.MethodDelete, sessionID, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		status, _ = callMCPTool(t, mcpServer.URL, sessionID)
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.Empty(t, s.sessionManager.sessions)
+	})
+
+	t.Run("rejects new sessions when full", func(t *testing.T) {
+		s, err := NewGraphQLSchemaServer("http://localhost:3002/graphql",
+			WithOperationsDir(""),
+			WithStateless(false),
+			WithToolRateLimit(ToolRateLimitOptions{Enabled: true, Requests: 10, Window: time.Minute, MaxSessions: 1}),
+		)
+		require.NoError(t, err)
+		require.NoError(t, s.Reload(newTestSchema(t)))
+
+		mcpServer := newTestMCPServer(t, s)
+
+		resp := initializeMCPSession(t, mcpServer.URL)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		sessionID := resp.Header.Get(server.HeaderKeySessionID)
+
+		resp = initializeMCPSession(t, mcpServer.URL)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+		// The existing session is not evicted
+		status, result := callMCPTool(t, mcpServer.URL, sessionID)
+		require.Equal(t, http.StatusOK, status)
+		assert.False(t, result.IsError)
+	})
+
+	t.Run("shares the limits in stateless mode", func(t *testing.T) {
+		s, err := NewGraphQLSchemaServer("http://localhost:3002/graphql",
+			WithOperationsDir(""),
+			WithStateless(true),
+			WithToolRateLimit(ToolRateLimitOptions{Enabled: true, Requests: 2, Window: time.Minute}),
+		)
+		require.NoError(t, err)
+		require.NoError(t, s.Reload(newTestSchema(t)))
+		assert.Nil(t, s.sessionManager)
+
+		mcpServer := newTestMCPServer(t, s)
+
+		status, result := callMCPTool(t, mcpServer.URL, "")
+		require.Equal(t, http.StatusOK, status)
+		assert.False(t, result.IsError)
+
+		// The session ID sent by the client is ignored
+		status, result = callMCPTool(t, mcpServer.URL, "mcp-session-abc")
+		require.Equal(t, http.StatusOK, status)
+		assert.False(t, result.IsError)
+
+		status, result = callMCPTool(t, mcpServer.URL, sessionIDPrefix+uuid.NewString())
+		require.Equal(t, http.StatusOK, status)
+		assert.True(t, result.IsError)
+		require.Len(t, result.Content, 1)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, errToolRateLimitExceeded.Error())
+	})
+}
+
+func TestToolRateLimitStatelessQuota(t *testing.T) {
+	_, err := NewGraphQLSchemaServer("http://localhost:3002/graphql",
+		WithStateless(true),
+		WithToolRateLimit(ToolRateLimitOptions{Enabled: true, Requests: 10, Window: time.Minute, Quota: 100}),
+	)
+	require.Error(t, err)
+
+	_, err = NewGraphQLSchemaServer("http://localhost:3002/graphql",
+		WithStateless(true),
+		WithToolRateLimit(ToolRateLimitOptions{Enabled: true, Requests: 10, Window: time.Minute}),
+	)
+	require.NoError(t, err)
+}
@@ -61,6 +61,56 @@ var skippedHeaders = map[string]struct{}{
 	"Sec-Websocket-Version":    {},
 }
 
+// HeaderForwardingOptions controls which MCP request headers are forwarded to the router
+type HeaderForwardingOptions struct {
+	// AllowList contains the names of the headers to forward, all headers are forwarded when empty
+	AllowList []string
+	// DenyList contains the names of the headers which are never forwarded, it takes precedence over the AllowList
+	DenyList []string
+}
+
+// headerForwarding holds the canonicalized header names of the HeaderForwardingOptions
+type headerForwarding struct {
+	allowedHeaders map[string]struct{}
+	deniedHeaders  map[string]struct{}
+}
+
+// newHeaderForwarding creates a headerForwarding from the given options
+func newHeaderForwarding(opts HeaderForwardingOptions) headerForwarding {
+	return headerForwarding{
+		allowedHeaders: canonicalHeaderSet(opts.AllowList),
+		deniedHeaders:  canonicalHeaderSet(opts.DenyList),
+	}
+}
+
+// canonicalHeaderSet returns the set of canonical header names or nil if there are none
+func canonicalHeaderSet(names []string) map[string]struct{} {
+	if len(names) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[http.CanonicalHeaderKey(name)] = struct{}{}
+	}
+	return set
+}
+
+// shouldForward reports whether the header with the given canonical name is forwarded to the router
+func (f headerForwarding) shouldForward(name string) bool {
+	// Hop-by-hop and content negotiation headers are never forwarded
+	if _, ok := skippedHeaders[name]; ok {
+		return false
+	}
+	if _, ok := f.deniedHeaders[name]; ok {
+		return false
+	}
+	if f.allowedHeaders == nil {
+		return true
+	}
+	_, ok := f.allowedHeaders[name]
+	return ok
+}
+
 // headersFromContext extracts the request headers from the context.
 func headersFromContext(ctx context.Context) (http.Header, error) {
 	headers, ok := ctx.Value(requestHeadersKey{}).(http.Header)
@@ -94,6 +144,12 @@ type Options struct {
 	Stateless bool
 	// CorsConfig is the CORS configuration for the MCP server
 	CorsConfig cors.Config
+	// ToolRateLimit is the rate limit configuration for tool invocations
+	ToolRateLimit ToolRateLimitOptions
+	// HeaderForwarding controls which request headers are forwarded to the router
+	HeaderForwarding HeaderForwardingOptions
+	// AuditLogger records every tool invocation, audit logs are disabled when nil
+	AuditLogger *zap.Logger
 }
 
 // GraphQLSchemaServer represents an MCP server that works with GraphQL schemas and operations
@@ -115,6 +171,8 @@ type GraphQLSchemaServer struct {
 	schemaCompiler            *SchemaCompiler
 	registeredTools           []string
 	corsConfig                cors.Config
+	headerForwarding          headerForwarding
+	sessionManager            *sessionManager
 }
 
 type graphqlRequest struct {
@@ -213,14 +271,41 @@ func NewGraphQLSchemaServer(routerGraphQLEndpoint string, opts ...func(*Options)
 		opt(options)
 	}
 
+	serverOpts := []server.ServerOption{
+		// Prompt, Resources aren't supported yet in any of the popular platforms
+		server.WithToolCapabilities(true),
+		server.WithPaginationLimit(100),
+	}
+
+	// The first middleware wraps all others. The audit middleware is registered before the recovery,
+	// so that invocations which panic or are rejected by the rate limiter are recorded too.
+	if options.AuditLogger != nil {
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(auditMiddleware(options.AuditLogger)))
+	}
+
+	serverOpts = append(serverOpts, server.WithRecovery())
+
+	var sessions *sessionManager
+	if options.ToolRateLimit.Enabled {
+		rateLimiter, err := newToolRateLimiter(options.ToolRateLimit, options.Stateless)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tool rate limit configuration: %w", err)
+		}
+
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(rateLimiter.middleware))
+
+		// The limits are tied to the sessions issued by the server, so that clients can't reset them with made-up session IDs
+		if !options.Stateless {
+			sessions = newSessionManager(options.ToolRateLimit.SessionIdleTimeout, options.ToolRateLimit.MaxSessions, rateLimiter.removeSession)
+			serverOpts = append(serverOpts, server.WithHooks(sessions.hooks()))
+		}
+	}
+
 	// Create the MCP server
 	mcpServer := server.NewMCPServer(
 		"wundergraph-cosmo-"+strcase.ToKebab(options.GraphName),
 		"0.0.1",
-		// Prompt, Resources aren't supported yet in any of the popular platforms
-		server.WithToolCapabilities(true),
-		server.WithPaginationLimit(100),
-		server.WithRecovery(),
+		serverOpts...,
 	)
 
 	retryClient := retryablehttp.NewClient()
@@ -242,6 +327,8 @@ func NewGraphQLSchemaServer(routerGraphQLEndpoint string, opts ...func(*Options)
 		exposeSchema:              options.ExposeSchema,
 		stateless:                 options.Stateless,
 		corsConfig:                options.CorsConfig,
+		headerForwarding:          newHeaderForwarding(options.HeaderForwarding),
+		sessionManager:            sessions,
 	}
 
 	return gs, nil
@@ -307,6 +394,27 @@ func WithStateless(stateless bool) func(*Options) {
 	}
 }
 
+// WithToolRateLimit sets the rate limit for tool invocations per session
+func WithToolRateLimit(rateLimit ToolRateLimitOptions) func(*Options) {
+	return func(o *Options) {
+		o.ToolRateLimit = rateLimit
+	}
+}
+
+// WithHeaderForwarding sets which request headers are forwarded to the router
+func WithHeaderForwarding(headerForwarding HeaderForwardingOptions) func(*Options) {
+	return func(o *Options) {
+		o.HeaderForwarding = headerForwarding
+	}
+}
+
+// WithAuditLogger sets the logger used to record tool invocations
+func WithAuditLogger(auditLogger *zap.Logger) func(*Options) {
+	return func(o *Options) {
+		o.AuditLogger = auditLogger
+	}
+}
+
 func WithCORS(corsCfg cors.Config) func(*Options) {
 	return func(o *Options) {
 		// Force specific CORS settings for MCP server
@@ -330,22 +438,14 @@ func (s *GraphQLSchemaServer) Serve() (*server.StreamableHTTPServer, error) {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	streamableHTTPServer := server.NewStreamableHTTPServer(s.server,
-		server.WithStreamableHTTPServer(httpServer),
-		server.WithLogger(NewZapAdapter(s.logger.With(zap.String("component", "mcp-server")))),
-		server.WithStateLess(s.stateless),
-		server.WithHTTPContextFunc(requestHeadersFromRequest),
-		server.WithHeartbeatInterval(10*time.Second),
-	)
+	streamableHTTPServer := s.newStreamableHTTPServer(httpServer)
 
 	middleware := cors.New(s.corsConfig)
 
 	mux := http.NewServeMux()
 
 	// No OAuth protection - original behavior
-	mux.Handle("/mcp", middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		streamableHTTPServer.ServeHTTP(w, r)
-	})))
+	mux.Handle("/mcp", middleware(s.limitSessions(streamableHTTPServer)))
 
 	// Set the handler for the custom HTTP server
 	httpServer.Handler = mux
@@ -374,6 +474,36 @@ func (s *GraphQLSchemaServer) Serve() (*server.StreamableHTTPServer, error) {
 	return streamableHTTPServer, nil
 }
 
+// newStreamableHTTPServer creates the streamable HTTP transport of the MCP server
+func (s *GraphQLSchemaServer) newStreamableHTTPServer(httpServer *http.Server) *server.StreamableHTTPServer {
+	opts := []server.StreamableHTTPOption{
+		server.WithStreamableHTTPServer(httpServer),
+		server.WithLogger(NewZapAdapter(s.logger.With(zap.String("component", "mcp-server")))),
+		server.WithStateLess(s.stateless),
+		server.WithHTTPContextFunc(requestHeadersFromRequest),
+		server.WithHeartbeatInterval(10 * time.Second),
+	}
+
+	if s.sessionManager != nil {
+		opts = append(opts, server.WithSessionIdManager(s.sessionManager))
+	}
+
+	return server.NewStreamableHTTPServer(s.server, opts...)
+}
+
+// limitSessions rejects the initialization of new sessions once the maximum number of sessions is reached
+func (s *GraphQLSchemaServer) limitSessions(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Requests without a session ID initialize a new session
+		if s.sessionManager != nil && r.Method == http.MethodPost && r.Header.Get(server.HeaderKeySessionID) == "" && s.sessionManager.full() {
+			http.Error(w, "Too many MCP sessions", http.StatusServiceUnavailable)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 // Start loads operations and starts the server
 func (s *GraphQLSchemaServer) Start() error {
 
@@ -547,6 +677,11 @@ func (s *GraphQLSchemaServer) registerTools() error {
 			toolDescription = fmt.Sprintf("Executes the GraphQL operation '%s' of type %s.", op.Name, op.OperationType)
 		}
 
+		// Let clients know that the operation is deprecated so that they can migrate away from it
+		if op.IsDeprecated() {
+			toolDescription = deprecationNotice(&op) + " " + toolDescription
+		}
+
 		toolName := fmt.Sprintf("execute_operation_%s", operationToolName)
 		tool := mcp.NewToolWithRawSchema(
 			toolName,
@@ -608,8 +743,34 @@ func (s *GraphQLSchemaServer) handleOperation(handler *operationHandler) func(ct
 		}
 
 		// Execute the operation with the provided variables
-		return s.executeGraphQLQuery(ctx, handler.operation.OperationString, jsonBytes)
+		result, err := s.executeGraphQLQuery(ctx, handler.operation.OperationString, jsonBytes)
+		if err != nil || !handler.operation.IsDeprecated() {
+			return result, err
+		}
+
+		s.logger.Warn("deprecated MCP operation executed",
+			zap.String("operation", handler.operation.Name),
+			zap.String("sunset", handler.operation.Sunset()))
+
+		// Attach the deprecation details to the result metadata, similar to the Deprecation and Sunset HTTP headers
+		if result.Meta == nil {
+			result.Meta = make(map[string]any)
+		}
+		result.Meta["deprecation"] = true
+		if sunset := handler.operation.Sunset(); sunset != "" {
+			result.Meta["sunset"] = sunset
+		}
+
+		return result, nil
+	}
+}
+
+// deprecationNotice returns the notice prepended to the tool description of a deprecated operation
+func deprecationNotice(op *schemaloader.Operation) string {
+	if sunset := op.Sunset(); sunset != "" {
+		return fmt.Sprintf("DEPRECATED: this operation will be removed after %s.", sunset)
 	}
+	return "DEPRECATED: this operation will be removed in the future."
 }
 
 // handleGraphQLOperationInfo returns a handler function that provides detailed info for a specific operation.
@@ -707,16 +868,16 @@ func (s *GraphQLSchemaServer) executeGraphQLQuery(ctx context.Context, query str
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Forward all headers from the original MCP request to the GraphQL server
+	// Forward the headers from the original MCP request to the GraphQL server
 	// The router's header forwarding rules will then determine what gets sent to subgraphs
 	headers, err := headersFromContext(ctx)
 	if err != nil {
 		s.logger.Debug("failed to get headers from context", zap.Error(err))
 	} else {
-		// Copy all headers from the MCP request
+		// Copy the headers from the MCP request
 		for key, values := range headers {
 			// Skip headers that should not be forwarded
-			if _, ok := skippedHeaders[key]; ok {
+			if !s.headerForwarding.shouldForward(key) {
 				continue
 			}
 			for _, value := range values {
@@ -0,0 +1,256 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+const testSchema = `
+type Query {
+	employee(id: ID!): Employee
+}
+
+type Employee {
+	id: ID!
+}
+`
+
+// newTestRouter returns a router stub answering every GraphQL request with the given response
+func newTestRouter(t *testing.T, response string) *httptest.Server {
+	t.Helper()
+
+	router := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(router.Close)
+
+	return router
+}
+
+// newTestSchema parses the test schema merged with the base schema
+func newTestSchema(t *testing.T) *ast.Document {
+	t.Helper()
+
+	schemaDoc, report := astparser.ParseGraphqlDocumentString(testSchema)
+	require.False(t, report.HasErrors())
+	require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(&schemaDoc))
+
+	return &schemaDoc
+}
+
+// writeOperations writes the operation files to a new operations directory
+func writeOperations(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for filename, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, filename), []byte(content), 0644))
+	}
+
+	return dir
+}
+
+// handleMessage sends the JSON-RPC request to the MCP server and returns the result
+func handleMessage(t *testing.T, s *GraphQLSchemaServer, method string, params any) any {
+	t.Helper()
+
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	require.NoError(t, err)
+
+	response, ok := s.server.HandleMessage(context.Background(), message).(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected a successful JSON-RPC response")
+
+	return response.Result
+}
+
+func TestRequestHeadersContext(t *testing.T) {
+	t.Run("headers stored with withRequestHeaders are returned from the context", func(t *testing.T) {
+		headers := http.Header{"X-Custom-Header": []string{"value"}}
+
+		got, err := headersFromContext(withRequestHeaders(context.Background(), headers))
+		require.NoError(t, err)
+		assert.Equal(t, headers, got)
+	})
+
+	t.Run("missing headers return an error", func(t *testing.T) {
+		_, err := headersFromContext(context.Background())
+		require.Error(t, err)
+	})
+
+	t.Run("request headers are cloned into the context", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set("X-Custom-Header", "value")
+
+		ctx := requestHeadersFromRequest(context.Background(), req)
+
+		// Mutating the request afterward must not affect the stored headers
+		req.Header.Set("X-Custom-Header", "changed")
+
+		got, err := headersFromContext(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "value", got.Get("X-Custom-Header"))
+	})
+}
+
+func TestHeaderForwarding(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      HeaderForwardingOptions
+		forwarded []string
+		skipped   []string
+	}{
+		{
+			name:      "forwards all headers except skipped headers by default",
+			forwarded: []string{"Authorization", "X-Custom-Header"},
+			skipped:   []string{"Connection", "Content-Length", "Accept-Encoding"},
+		},
+		{
+			name:      "forwards only allowed headers",
+			opts:      HeaderForwardingOptions{AllowList: []string{"authorization", "X-REQUEST-ID"}},
+			forwarded: []string{"Authorization", "X-Request-Id"},
+			skipped:   []string{"X-Custom-Header"},
+		},
+		{
+			name:      "never forwards denied headers",
+			opts:      HeaderForwardingOptions{DenyList: []string{"cookie"}},
+			forwarded: []string{"Authorization"},
+			skipped:   []string{"Cookie"},
+		},
+		{
+			name:      "deny list takes precedence over the allow list",
+			opts:      HeaderForwardingOptions{AllowList: []string{"Authorization", "Cookie"}, DenyList: []string{"Cookie"}},
+			forwarded: []string{"Authorization"},
+			skipped:   []string{"Cookie", "X-Custom-Header"},
+		},
+		{
+			name:    "skipped headers can't be allowed",
+			opts:    HeaderForwardingOptions{AllowList: []string{"Connection"}},
+			skipped: []string{"Connection"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forwarding := newHeaderForwarding(tt.opts)
+			for _, name := range tt.forwarded {
+				assert.True(t, forwarding.shouldForward(name), "expected %s to be forwarded", name)
+			}
+			for _, name := range tt.skipped {
+				assert.False(t, forwarding.shouldForward(name), "expected %s to be skipped", name)
+			}
+		})
+	}
+}
+
+func TestExecuteGraphQLQueryForwardsHeaders(t *testing.T) {
+	var capturedHeaders http.Header
+	router := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedHeaders = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"ok":true}}`))
+	}))
+	defer router.Close()
+
+	s, err := NewGraphQLSchemaServer(router.URL, WithHeaderForwarding(HeaderForwardingOptions{
+		AllowList: []string{"Authorization", "X-Custom-Header", "Cookie"},
+		DenyList:  []string{"Cookie"},
+	}))
+	require.NoError(t, err)
+
+	ctx := withRequestHeaders(context.Background(), http.Header{
+		"Authorization":   []string{"Bearer token"},
+		"X-Custom-Header": []string{"value"},
+		"X-Other-Header":  []string{"other"},
+		"Cookie":          []string{"session=secret"},
+		"Accept":          []string{"text/event-stream"},
+	})
+
+	result, err := s.executeGraphQLQuery(ctx, "query { ok }", nil)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+
+	require.NotNil(t, capturedHeaders)
+	assert.Equal(t, "Bearer token", capturedHeaders.Get("Authorization"))
+	assert.Equal(t, "value", capturedHeaders.Get("X-Custom-Header"))
+	assert.Empty(t, capturedHeaders.Get("X-Other-Header"))
+	assert.Empty(t, capturedHeaders.Get("Cookie"))
+	assert.Equal(t, "application/json", capturedHeaders.Get("Accept"))
+	assert.Equal(t, "application/json; charset=utf-8", capturedHeaders.Get("Content-Type"))
+}
+
+func TestDeprecatedOperation(t *testing.T) {
+	router := newTestRouter(t, `{"data":{"employee":{"id":"1"}}}`)
+
+	operationsDir := writeOperations(t, map[string]string{
+		"FindEmployee.graphql": `# connect: sunset=2025-12-31
+"""Finds an employee by ID"""
+query FindEmployee($id: ID!) {
+	employee(id: $id) {
+		id
+	}
+}`,
+		"GetEmployee.graphql": `query GetEmployee($id: ID!) {
+	employee(id: $id) {
+		id
+	}
+}`,
+	})
+
+	core, logs := observer.New(zap.WarnLevel)
+	s, err := NewGraphQLSchemaServer(router.URL, WithOperationsDir(operationsDir), WithLogger(zap.New(core)))
+	require.NoError(t, err)
+	require.NoError(t, s.Reload(newTestSchema(t)))
+
+	listResult, ok := handleMessage(t, s, "tools/list", map[string]any{}).(mcp.ListToolsResult)
+	require.True(t, ok)
+
+	descriptions := make(map[string]string)
+	for _, tool := range listResult.Tools {
+		descriptions[tool.Name] = tool.Description
+	}
+	assert.Equal(t, "DEPRECATED: this operation will be removed after 2025-12-31. Finds an employee by ID", descriptions["execute_operation_find_employee"])
+	assert.Equal(t, "Executes the GraphQL operation 'GetEmployee' of type query.", descriptions["execute_operation_get_employee"])
+
+	callTool := func(name string) mcp.CallToolResult {
+		result, ok := handleMessage(t, s, "tools/call", map[string]any{
+			"name":      name,
+			"arguments": map[string]any{"id": "1"},
+		}).(mcp.CallToolResult)
+		require.True(t, ok)
+		require.False(t, result.IsError)
+		return result
+	}
+
+	result := callTool("execute_operation_find_employee")
+	assert.Equal(t, map[string]any{"deprecation": true, "sunset": "2025-12-31"}, result.Meta)
+
+	require.Equal(t, 1, logs.Len())
+	entry := logs.All()[0]
+	assert.Equal(t, "deprecated MCP operation executed", entry.Message)
+	assert.Equal(t, "FindEmployee", entry.ContextMap()["operation"])
+	assert.Equal(t, "2025-12-31", entry.ContextMap()["sunset"])
+
+	result = callTool("execute_operation_get_employee")
+	assert.Empty(t, result.Meta)
+	assert.Equal(t, 1, logs.Len())
+}
@@ -0,0 +1,172 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// sessionIDPrefix is the prefix of the session IDs issued by the session manager
+	sessionIDPrefix = "mcp-session-"
+	// defaultSessionIdleTimeout is the duration after which a session without any activity is dropped
+	defaultSessionIdleTimeout = 30 * time.Minute
+	// defaultMaxSessions is the maximum number of sessions tracked at the same time
+	defaultMaxSessions = 10_000
+)
+
+// sessionManager issues and tracks the session IDs of the streamable HTTP server.
+// Unlike the default session ID manager of mcp-go, only the session IDs it issued are accepted, so that clients
+// can't make up session IDs, e.g. to get fresh rate limits. Sessions without activity are dropped after the idle
+// timeout, sessions with an open stream never expire. Once the maximum number of sessions is reached, new sessions
+// are rejected rather than evicting active ones.
+type sessionManager struct {
+	mu          sync.Mutex
+	sessions    map[string]*trackedSession
+	idleTimeout time.Duration
+	maxSessions int
+	now         func() time.Time
+	// onRemove is called with the ID of every session that is dropped
+	onRemove func(sessionID string)
+}
+
+// trackedSession holds the activity of a single session
+type trackedSession struct {
+	// lastSeen is the time of the latest request of the session
+	lastSeen time.Time
+	// streams is the number of open streams of the session
+	streams int
+}
+
+var _ server.SessionIdManager = (*sessionManager)(nil)
+
+// newSessionManager creates a new sessionManager. Zero values fall back to the defaults.
+func newSessionManager(idleTimeout time.Duration, maxSessions int, onRemove func(sessionID string)) *sessionManager {
+	if idleTimeout <= 0 {
+		idleTimeout = defaultSessionIdleTimeout
+	}
+	if maxSessions <= 0 {
+		maxSessions = defaultMaxSessions
+	}
+
+	return &sessionManager{
+		sessions:    make(map[string]*trackedSession),
+		idleTimeout: idleTimeout,
+		maxSessions: maxSessions,
+		now:         time.Now,
+		onRemove:    onRemove,
+	}
+}
+
+// Generate issues a new session ID. When the maximum number of sessions is reached, the session isn't tracked,
+// so that its requests are rejected. Use full to reject the initialization of the session upfront.
+func (m *sessionManager) Generate() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	sessionID := sessionIDPrefix + uuid.NewString()
+
+	m.removeIdleSessions(now)
+	if len(m.sessions) < m.maxSessions {
+		m.sessions[sessionID] = &trackedSession{lastSeen: now}
+	}
+
+	return sessionID
+}
+
+// Validate accepts the session IDs issued by Generate. Unknown, expired and terminated sessions are
+// reported as terminated, so that clients start a new session.
+func (m *sessionManager) Validate(sessionID string) (isTerminated bool, err error) {
+	if sessionID == "" {
+		return false, errors.New("missing session id")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	session, ok := m.sessions[sessionID]
+	if !ok {
+		return true, nil
+	}
+	if m.isIdle(session, now) {
+		m.remove(sessionID)
+		return true, nil
+	}
+
+	session.lastSeen = now
+	return false, nil
+}
+
+// Terminate drops the session
+func (m *sessionManager) Terminate(sessionID string) (isNotAllowed bool, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sessions[sessionID]; ok {
+		m.remove(sessionID)
+	}
+	return false, nil
+}
+
+// full reports whether the maximum number of sessions is reached after dropping the idle sessions
+func (m *sessionManager) full() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.removeIdleSessions(m.now())
+	return len(m.sessions) >= m.maxSessions
+}
+
+// hooks returns the server hooks keeping the sessions with an open stream alive
+func (m *sessionManager) hooks() *server.Hooks {
+	hooks := &server.Hooks{}
+
+	hooks.AddOnRegisterSession(func(_ context.Context, session server.ClientSession) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		if tracked, ok := m.sessions[session.SessionID()]; ok {
+			tracked.streams++
+		}
+	})
+
+	hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		if tracked, ok := m.sessions[session.SessionID()]; ok && tracked.streams > 0 {
+			tracked.streams--
+			tracked.lastSeen = m.now()
+		}
+	})
+
+	return hooks
+}
+
+// isIdle reports whether the session has no open stream and no request within the idle timeout
+func (m *sessionManager) isIdle(session *trackedSession, now time.Time) bool {
+	return session.streams == 0 && now.Sub(session.lastSeen) > m.idleTimeout
+}
+
+// removeIdleSessions drops all idle sessions
+func (m *sessionManager) removeIdleSessions(now time.Time) {
+	for sessionID, session := range m.sessions {
+		if m.isIdle(session, now) {
+			m.remove(sessionID)
+		}
+	}
+}
+
+// remove drops the session and notifies the onRemove callback
+func (m *sessionManager) remove(sessionID string) {
+	delete(m.sessions, sessionID)
+	if m.onRemove != nil {
+		m.onRemove(sessionID)
+	}
+}
@@ -0,0 +1,137 @@
+package mcpserver
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSession is a minimal client session with a fixed ID
+type testSession struct {
+	id string
+}
+
+func (s testSession) Initialize()                                         {}
+func (s testSession) Initialized() bool                                   { return true }
+func (s testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s testSession) SessionID() string                                   { return s.id }
+
+func TestSessionManager(t *testing.T) {
+	t.Run("only accepts issued sessions", func(t *testing.T) {
+		manager := newSessionManager(0, 0, nil)
+
+		sessionID := manager.Generate()
+		assert.True(t, strings.HasPrefix(sessionID, sessionIDPrefix))
+
+		isTerminated, err := manager.Validate(sessionID)
+		require.NoError(t, err)
+		assert.False(t, isTerminated)
+
+		// Made-up session IDs are neither accepted nor tracked
+		isTerminated, err = manager.Validate(sessionIDPrefix + uuid.NewString())
+		require.NoError(t, err)
+		assert.True(t, isTerminated)
+		assert.Len(t, manager.sessions, 1)
+
+		_, err = manager.Validate("")
+		require.Error(t, err)
+	})
+
+	t.Run("notifies about terminated sessions", func(t *testing.T) {
+		var removed []string
+		manager := newSessionManager(0, 0, func(sessionID string) {
+			removed = append(removed, sessionID)
+		})
+
+		sessionID := manager.Generate()
+		_, err := manager.Terminate(sessionID)
+		require.NoError(t, err)
+
+		isTerminated, err := manager.Validate(sessionID)
+		require.NoError(t, err)
+		assert.True(t, isTerminated)
+		assert.Equal(t, []string{sessionID}, removed)
+	})
+
+	t.Run("drops idle sessions", func(t *testing.T) {
+		var removed []string
+		manager := newSessionManager(time.Minute, 0, func(sessionID string) {
+			removed = append(removed, sessionID)
+		})
+
+		now := time.Now()
+		manager.now = func() time.Time { return now }
+
+		idleSession := manager.Generate()
+		activeSession := manager.Generate()
+
+		now = now.Add(30 * time.Second)
+		_, err := manager.Validate(activeSession)
+		require.NoError(t, err)
+
+		now = now.Add(31 * time.Second)
+		isTerminated, err := manager.Validate(idleSession)
+		require.NoError(t, err)
+		assert.True(t, isTerminated)
+
+		isTerminated, err = manager.Validate(activeSession)
+		require.NoError(t, err)
+		assert.False(t, isTerminated)
+
+		assert.Equal(t, []string{idleSession}, removed)
+	})
+
+	t.Run("keeps sessions with an open stream alive", func(t *testing.T) {
+		manager := newSessionManager(time.Minute, 0, nil)
+		hooks := manager.hooks()
+
+		now := time.Now()
+		manager.now = func() time.Time { return now }
+
+		session := testSession{id: manager.Generate()}
+		hooks.RegisterSession(context.Background(), session)
+
+		now = now.Add(time.Hour)
+		assert.False(t, manager.full())
+		isTerminated, err := manager.Validate(session.id)
+		require.NoError(t, err)
+		assert.False(t, isTerminated)
+
+		// The idle timeout starts once the stream is closed
+		hooks.UnregisterSession(context.Background(), session)
+		now = now.Add(2 * time.Minute)
+		isTerminated, err = manager.Validate(session.id)
+		require.NoError(t, err)
+		assert.True(t, isTerminated)
+	})
+
+	t.Run("doesn't evict sessions when full", func(t *testing.T) {
+		manager := newSessionManager(time.Minute, 2, nil)
+
+		now := time.Now()
+		manager.now = func() time.Time { return now }
+
+		first := manager.Generate()
+		second := manager.Generate()
+		assert.True(t, manager.full())
+
+		// Sessions issued at capacity aren't tracked, the existing sessions are kept
+		rejected := manager.Generate()
+		isTerminated, err := manager.Validate(rejected)
+		require.NoError(t, err)
+		assert.True(t, isTerminated)
+		assert.Contains(t, manager.sessions, first)
+		assert.Contains(t, manager.sessions, second)
+
+		// Idle sessions free up capacity
+		now = now.Add(2 * time.Minute)
+		assert.False(t, manager.full())
+		assert.Empty(t, manager.sessions)
+	})
+}
@@ -4,12 +4,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astnormalization"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvalidation"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
 	"go.uber.org/zap"
@@ -24,6 +29,52 @@ type Operation struct {
 	Description     string
 	JSONSchema      json.RawMessage
 	OperationType   string // "query", "mutation", or "subscription"
+	// Annotations holds the key/value pairs declared in the "# connect:" comment block above the operation definition
+	Annotations map[string]string
+}
+
+// annotationPrefix is the comment prefix used to declare operation annotations, e.g. "# connect: timeout=5s cache=30s"
+const annotationPrefix = "connect:"
+
+const (
+	// AnnotationDeprecated marks an operation as deprecated, e.g. "# connect: deprecated"
+	AnnotationDeprecated = "deprecated"
+	// AnnotationSunset declares the date after which a deprecated operation is removed, e.g. "# connect: sunset=2025-12-31"
+	AnnotationSunset = "sunset"
+)
+
+// IsDeprecated returns true if the operation is annotated as deprecated or has a sunset date
+func (o *Operation) IsDeprecated() bool {
+	if _, ok := o.Annotations[AnnotationSunset]; ok {
+		return true
+	}
+	return o.Annotations[AnnotationDeprecated] == "true"
+}
+
+// Sunset returns the sunset date of the operation or an empty string if none is declared
+func (o *Operation) Sunset() string {
+	return o.Annotations[AnnotationSunset]
+}
+
+// validateDeprecationAnnotations checks that the deprecated annotation is a boolean and the sunset annotation is a date
+func validateDeprecationAnnotations(annotations map[string]string) error {
+	if deprecated, ok := annotations[AnnotationDeprecated]; ok && deprecated != "true" && deprecated != "false" {
+		return fmt.Errorf("invalid %s annotation %q: must be true or false", AnnotationDeprecated, deprecated)
+	}
+
+	if sunset, ok := annotations[AnnotationSunset]; ok {
+		// A sunset date always deprecates the operation
+		if annotations[AnnotationDeprecated] == "false" {
+			return fmt.Errorf("invalid %s annotation %q: a sunset date can't be declared for an operation which is not deprecated", AnnotationSunset, sunset)
+		}
+		if _, err := time.Parse(time.DateOnly, sunset); err != nil {
+			if _, err := time.Parse(time.RFC3339, sunset); err != nil {
+				return fmt.Errorf("invalid %s annotation %q: must be a date in the format 2006-01-02 or RFC 3339", AnnotationSunset, sunset)
+			}
+		}
+	}
+
+	return nil
 }
 
 // OperationLoader loads GraphQL operations from files in a directory
@@ -42,14 +93,33 @@ func NewOperationLoader(logger *zap.Logger, schemaDoc *ast.Document) *OperationL
 	}
 }
 
-// LoadOperationsFromDirectory loads all GraphQL operations from files in the specified directory
+// operationFile is a parsed GraphQL file containing at least one operation definition
+type operationFile struct {
+	path     string
+	content  string
+	document ast.Document
+}
+
+// LoadOperationsFromDirectory loads all GraphQL operations from files in the specified directory.
+// A file may contain several named operations, each of them is registered individually.
+// Files that only contain fragment definitions are shared fragments which can be spread in any operation.
 func (l *OperationLoader) LoadOperationsFromDirectory(dirPath string) ([]Operation, error) {
 	var operations []Operation
+	var files []operationFile
+
+	// Shared fragment definitions by name
+	fragments := make(map[string]string)
 
 	// Create an operation validator
 	validator := astvalidation.DefaultOperationValidator()
 
-	// Walk through the directory and process GraphQL files
+	// Create a normalizer to inline fragment spreads before validation
+	normalizer := astnormalization.NewWithOpts(
+		astnormalization.WithInlineFragmentSpreads(),
+		astnormalization.WithRemoveFragmentDefinitions(),
+	)
+
+	// Walk through the directory and collect GraphQL files and shared fragments
 	err := filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -71,49 +141,201 @@ func (l *OperationLoader) LoadOperationsFromDirectory(dirPath string) ([]Operati
 			return fmt.Errorf("failed to read file %s: %w", path, err)
 		}
 
-		// Parse the operation
-		operationString := string(content)
-		opDoc, err := parseOperation(path, operationString)
+		// Parse the document
+		doc, err := parseDocument(string(content))
 		if err != nil {
 			l.Logger.Error("Failed to parse MCP operation", zap.String("file", path), zap.Error(err))
 			return nil
 		}
 
+		if len(doc.OperationDefinitions) > 0 {
+			files = append(files, operationFile{
+				path:     path,
+				content:  string(content),
+				document: doc,
+			})
+			return nil
+		}
+
+		if len(doc.FragmentDefinitions) == 0 {
+			l.Logger.Error("Failed to parse MCP operation", zap.String("file", path), zap.Error(fmt.Errorf("expected at least one operation or fragment definition in file %s", path)))
+			return nil
+		}
+
+		// The file only contains fragments, register them as shared fragments
+		for _, node := range doc.RootNodes {
+			if node.Kind != ast.NodeKindFragmentDefinition {
+				continue
+			}
+			fragmentName := doc.FragmentDefinitionNameString(node.Ref)
+			if _, exists := fragments[fragmentName]; exists {
+				l.Logger.Error("MCP fragment already exists", zap.String("fragment", fragmentName), zap.String("file", path))
+				continue
+			}
+			fragment, err := printDefinitions(&doc, []ast.Node{node})
+			if err != nil {
+				l.Logger.Error("Failed to print MCP fragment", zap.String("fragment", fragmentName), zap.String("file", path), zap.Error(err))
+				continue
+			}
+			fragments[fragmentName] = fragment
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error walking mcp operations directory %s: %w", dirPath, err)
+	}
+
+	for _, file := range files {
+		operations = l.loadOperationsFromFile(file, fragments, validator, normalizer, operations)
+	}
+
+	return operations, nil
+}
+
+// loadOperationsFromFile extracts, validates and appends every operation of the file to the operations
+func (l *OperationLoader) loadOperationsFromFile(file operationFile, fragments map[string]string, validator *astvalidation.OperationValidator, normalizer *astnormalization.OperationNormalizer, operations []Operation) []Operation {
+	path := file.path
+
+	// Resolve fragment spreads which are not defined in the file itself with the shared fragments
+	var err error
+	doc := file.document
+	needsSharedFragments := false
+	for ref := range doc.FragmentSpreads {
+		if _, exists := doc.FragmentDefinitionRef(doc.FragmentSpreadNameBytes(ref)); !exists {
+			needsSharedFragments = true
+			break
+		}
+	}
+
+	if needsSharedFragments {
+		source := file.content
+		for _, fragmentName := range slices.Sorted(maps.Keys(fragments)) {
+			if _, exists := file.document.FragmentDefinitionRef([]byte(fragmentName)); exists {
+				continue
+			}
+			source += "\n\n" + fragments[fragmentName]
+		}
+
+		doc, err = parseDocument(source)
+		if err != nil {
+			l.Logger.Error("Failed to parse MCP operation", zap.String("file", path), zap.Error(err))
+			return operations
+		}
+	}
+
+	// Anonymous operations are only allowed when they are the only operation in the document
+	operationCount := len(doc.OperationDefinitions)
+	if operationCount > 1 {
+		for ref := range doc.OperationDefinitions {
+			if doc.OperationDefinitions[ref].Name.Length() == 0 {
+				l.Logger.Error("Anonymous MCP operation must be the only operation in the file", zap.String("file", path))
+				return operations
+			}
+		}
+	}
+
+	// Every fragment defined in the file must be used by one of its operations. The check runs on the
+	// un-normalized document, as the normalizer removes the fragment definitions before validation.
+	usedFragments := make(map[int]struct{})
+	for ref := range doc.OperationDefinitions {
+		collectFragments(&doc, doc.OperationDefinitions[ref].SelectionSet, usedFragments)
+	}
+	for _, node := range file.document.RootNodes {
+		if node.Kind != ast.NodeKindFragmentDefinition {
+			continue
+		}
+		fragmentRef, _ := doc.FragmentDefinitionRef(file.document.FragmentDefinitionNameBytes(node.Ref))
+		if _, used := usedFragments[fragmentRef]; !used {
+			l.Logger.Error("Invalid MCP operation",
+				zap.String("file", path),
+				zap.String("errors", fmt.Sprintf("fragment: %s defined but not used", file.document.FragmentDefinitionNameString(node.Ref))))
+			return operations
+		}
+	}
+
+	for _, node := range doc.RootNodes {
+		if node.Kind != ast.NodeKindOperationDefinition {
+			continue
+		}
+
+		// Parse the annotations from the comment block directly above the operation definition
+		annotations, err := parseAnnotations(commentBlockAbove(file.content, operationDefinitionLine(&doc, node.Ref)))
+		if err == nil {
+			err = validateDeprecationAnnotations(annotations)
+		}
+		if err != nil {
+			l.Logger.Error("Failed to parse MCP operation annotations", zap.String("operation", doc.OperationDefinitionNameString(node.Ref)), zap.String("file", path), zap.Error(err))
+			continue
+		}
+
+		// Keep the file content as is when it holds a single self-contained operation
+		operationString := file.content
+		if operationCount > 1 || needsSharedFragments {
+			operationString, err = extractOperation(&doc, node.Ref)
+			if err != nil {
+				l.Logger.Error("Failed to extract MCP operation", zap.String("operation", doc.OperationDefinitionNameString(node.Ref)), zap.String("file", path), zap.Error(err))
+				continue
+			}
+		}
+
+		opDoc, err := parseDocument(operationString)
+		if err != nil {
+			l.Logger.Error("Failed to parse MCP operation", zap.String("file", path), zap.Error(err))
+			continue
+		}
+
 		// Extract the operation name and type
 		opName, opType, err := getOperationNameAndType(&opDoc)
 		if err != nil {
 			l.Logger.Error("Failed to extract MCP operation name and type", zap.String("operation", opName), zap.String("file", path), zap.Error(err))
-			return nil
+			continue
 		}
 
 		// Check if the operation type is supported
 		if opType == "subscription" {
 			l.Logger.Error("Subscriptions in MCP are not supported yet", zap.String("operation", opName), zap.String("file", path))
-			return nil
+			continue
 		}
 
 		// Validate operation against schema
 		validationReport := operationreport.Report{}
-		validationState := validator.Validate(&opDoc, l.SchemaDocument, &validationReport)
+		validationDoc := &opDoc
+
+		// Fragment spreads are inlined on a copy of the operation as the validator expects them to be resolved
+		if len(opDoc.FragmentDefinitions) > 0 {
+			normalizedDoc, err := parseDocument(operationString)
+			if err != nil {
+				l.Logger.Error("Failed to parse MCP operation", zap.String("file", path), zap.Error(err))
+				continue
+			}
+			normalizer.NormalizeOperation(&normalizedDoc, l.SchemaDocument, &validationReport)
+			validationDoc = &normalizedDoc
+		}
+
+		validationState := astvalidation.Invalid
+		if !validationReport.HasErrors() {
+			validationState = validator.Validate(validationDoc, l.SchemaDocument, &validationReport)
+		}
 		if validationState == astvalidation.Invalid {
 			l.Logger.Error("Invalid MCP operation",
 				zap.String("operation", opName),
 				zap.String("file", path),
 				zap.String("errors", validationReport.Error()))
-			return nil
+			continue
 		}
 
 		// if not the operation name, use the file name without the extension
 		if opName == "" {
-			opName = strings.TrimSuffix(d.Name(), filepath.Ext(d.Name()))
+			fileName := filepath.Base(path)
+			opName = strings.TrimSuffix(fileName, filepath.Ext(fileName))
 		}
 
 		// Check if the operation name is unique
-		for _, op := range operations {
-			if op.Name == opName {
-				l.Logger.Error("MCP operation already exists", zap.String("operation", opName), zap.String("file", path))
-				return nil
-			}
+		if slices.ContainsFunc(operations, func(op Operation) bool { return op.Name == opName }) {
+			l.Logger.Error("MCP operation already exists", zap.String("operation", opName), zap.String("file", path))
+			continue
 		}
 
 		// Extract description from operation definition
@@ -127,16 +349,11 @@ func (l *OperationLoader) LoadOperationsFromDirectory(dirPath string) ([]Operati
 			OperationString: operationString,
 			OperationType:   opType,
 			Description:     opDescription,
+			Annotations:     annotations,
 		})
-
-		return nil
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("error walking mcp operations directory %s: %w", dirPath, err)
 	}
 
-	return operations, nil
+	return operations
 }
 
 // isGraphQLFile checks if a file is a GraphQL file based on its extension
@@ -145,19 +362,75 @@ func isGraphQLFile(path string) bool {
 	return ext == ".graphql" || ext == ".gql"
 }
 
-// parseOperation parses a GraphQL operation string into an AST document
-func parseOperation(path string, operation string) (ast.Document, error) {
-	opDoc, report := astparser.ParseGraphqlDocumentString(operation)
+// parseDocument parses a GraphQL document string into an AST document
+func parseDocument(document string) (ast.Document, error) {
+	doc, report := astparser.ParseGraphqlDocumentString(document)
 	if report.HasErrors() {
 		return ast.Document{}, fmt.Errorf("parsing errors: %s", report.Error())
 	}
 
-	operationCount := len(opDoc.OperationDefinitions)
-	if operationCount != 1 {
-		return ast.Document{}, fmt.Errorf("expected exactly one operation definition in file %s, got %d", path, operationCount)
+	return doc, nil
+}
+
+// extractOperation prints the operation together with all fragments it uses, directly or transitively
+func extractOperation(doc *ast.Document, operationRef int) (string, error) {
+	nodes := []ast.Node{{Kind: ast.NodeKindOperationDefinition, Ref: operationRef}}
+
+	usedFragments := make(map[int]struct{})
+	collectFragments(doc, doc.OperationDefinitions[operationRef].SelectionSet, usedFragments)
+
+	// Keep the fragments in document order
+	for _, node := range doc.RootNodes {
+		if node.Kind != ast.NodeKindFragmentDefinition {
+			continue
+		}
+		if _, ok := usedFragments[node.Ref]; ok {
+			nodes = append(nodes, node)
+		}
 	}
 
-	return opDoc, nil
+	return printDefinitions(doc, nodes)
+}
+
+// collectFragments collects the refs of all fragment definitions spread in the selection set
+func collectFragments(doc *ast.Document, selectionSetRef int, fragments map[int]struct{}) {
+	for _, selectionRef := range doc.SelectionSets[selectionSetRef].SelectionRefs {
+		selection := doc.Selections[selectionRef]
+		switch selection.Kind {
+		case ast.SelectionKindField:
+			if doc.Fields[selection.Ref].HasSelections {
+				collectFragments(doc, doc.Fields[selection.Ref].SelectionSet, fragments)
+			}
+		case ast.SelectionKindInlineFragment:
+			if doc.InlineFragments[selection.Ref].HasSelections {
+				collectFragments(doc, doc.InlineFragments[selection.Ref].SelectionSet, fragments)
+			}
+		case ast.SelectionKindFragmentSpread:
+			fragmentRef, exists := doc.FragmentDefinitionRef(doc.FragmentSpreadNameBytes(selection.Ref))
+			if !exists {
+				continue
+			}
+			if _, seen := fragments[fragmentRef]; seen {
+				continue
+			}
+			fragments[fragmentRef] = struct{}{}
+			if doc.FragmentDefinitions[fragmentRef].HasSelections {
+				collectFragments(doc, doc.FragmentDefinitions[fragmentRef].SelectionSet, fragments)
+			}
+		}
+	}
+}
+
+// printDefinitions prints the given root nodes of the document
+func printDefinitions(doc *ast.Document, nodes []ast.Node) (string, error) {
+	rootNodes := doc.RootNodes
+	defer func() {
+		doc.RootNodes = rootNodes
+	}()
+
+	doc.RootNodes = nodes
+
+	return astprinter.PrintStringIndent(doc, "  ")
 }
 
 // getOperationNameAndType extracts the name and type of the first operation in a document
@@ -200,3 +473,73 @@ func extractOperationDescription(doc *ast.Document) string {
 	}
 	return ""
 }
+
+// operationDefinitionLine returns the line on which the operation definition starts, including its description
+func operationDefinitionLine(doc *ast.Document, operationRef int) int {
+	opDef := doc.OperationDefinitions[operationRef]
+	if opDef.Description.IsDefined {
+		return int(opDef.Description.Position.LineStart)
+	}
+	if opDef.OperationTypeLiteral.LineStart > 0 {
+		return int(opDef.OperationTypeLiteral.LineStart)
+	}
+	// Anonymous operations in shorthand form start with their selection set
+	return int(doc.SelectionSets[opDef.SelectionSet].LBrace.LineStart)
+}
+
+// commentBlockAbove returns the comment and blank lines directly above the given line of the content
+func commentBlockAbove(content string, line int) string {
+	lines := strings.Split(content, "\n")
+	end := min(max(line-1, 0), len(lines))
+
+	start := end
+	for start > 0 {
+		previous := strings.TrimSpace(lines[start-1])
+		if previous != "" && !strings.HasPrefix(previous, "#") {
+			break
+		}
+		start--
+	}
+
+	return strings.Join(lines[start:end], "\n")
+}
+
+// parseAnnotations parses the "# connect:" comment lines of the comment block above an operation definition.
+// Each line holds whitespace separated key=value pairs. A key without a value is treated as "true".
+// Parsing stops at the first line that is neither blank nor a comment.
+func parseAnnotations(content string) (map[string]string, error) {
+	var annotations map[string]string
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+
+		comment := strings.TrimSpace(strings.TrimPrefix(line, "#"))
+		if !strings.HasPrefix(comment, annotationPrefix) {
+			continue
+		}
+
+		for _, pair := range strings.Fields(strings.TrimPrefix(comment, annotationPrefix)) {
+			key, value, found := strings.Cut(pair, "=")
+			if key == "" {
+				return nil, fmt.Errorf("invalid annotation %q: missing key", pair)
+			}
+			if !found {
+				value = "true"
+			} else if value == "" {
+				return nil, fmt.Errorf("invalid annotation %q: missing value", pair)
+			}
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			annotations[key] = value
+		}
+	}
+
+	return annotations, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 // TestLoadOperationsWithDescriptions tests that the OperationLoader properly loads
@@ -165,3 +166,288 @@ func TestLoadOperationsFromEmptyDirectory(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, operations, 0, "Empty directory should return no operations")
 }
+
+// TestLoadOperationsWithoutDefinitions tests that files without operations and fragments are reported
+func TestLoadOperationsWithoutDefinitions(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := map[string]string{
+		"Empty.graphql":  "",
+		"Schema.graphql": "type Employee { id: ID! }",
+	}
+
+	for filename, content := range testFiles {
+		err := os.WriteFile(filepath.Join(tempDir, filename), []byte(content), 0644)
+		require.NoError(t, err, "Failed to write test file %s", filename)
+	}
+
+	schemaStr := `type Query { test: String }`
+	schemaDoc, report := astparser.ParseGraphqlDocumentString(schemaStr)
+	require.False(t, report.HasErrors())
+
+	core, logs := observer.New(zap.ErrorLevel)
+	loader := NewOperationLoader(zap.New(core), &schemaDoc)
+	operations, err := loader.LoadOperationsFromDirectory(tempDir)
+	require.NoError(t, err)
+	assert.Len(t, operations, 0)
+
+	files := make([]string, 0, logs.Len())
+	for _, entry := range logs.All() {
+		files = append(files, filepath.Base(entry.ContextMap()["file"].(string)))
+	}
+	assert.ElementsMatch(t, []string{"Empty.graphql", "Schema.graphql"}, files)
+}
+
+// TestLoadOperationsWithAnnotations tests that the "# connect:" comment header
+// is parsed and attached to the loaded operation
+func TestLoadOperationsWithAnnotations(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := map[string]string{
+		"Annotated.graphql": `# connect: timeout=5s cache=30s
+# Regular comments are ignored
+# connect: auth=required deprecated
+query GetValue {
+	validField
+}`,
+		"NotAnnotated.graphql": `query GetOtherValue {
+	validField
+}`,
+		"Malformed.graphql": `# connect: timeout=
+query GetBrokenValue {
+	validField
+}`,
+	}
+
+	for filename, content := range testFiles {
+		err := os.WriteFile(filepath.Join(tempDir, filename), []byte(content), 0644)
+		require.NoError(t, err, "Failed to write test file %s", filename)
+	}
+
+	schemaStr := `type Query { validField: String }`
+	schemaDoc, report := astparser.ParseGraphqlDocumentString(schemaStr)
+	require.False(t, report.HasErrors())
+
+	err := asttransform.MergeDefinitionWithBaseSchema(&schemaDoc)
+	require.NoError(t, err)
+
+	loader := NewOperationLoader(zap.NewNop(), &schemaDoc)
+	operations, err := loader.LoadOperationsFromDirectory(tempDir)
+	require.NoError(t, err)
+
+	// The operation with malformed annotations should be skipped
+	require.Len(t, operations, 2)
+
+	opMap := make(map[string]Operation)
+	for _, op := range operations {
+		opMap[op.Name] = op
+	}
+
+	assert.Equal(t, map[string]string{
+		"timeout":    "5s",
+		"cache":      "30s",
+		"auth":       "required",
+		"deprecated": "true",
+	}, opMap["GetValue"].Annotations)
+	assert.Empty(t, opMap["GetOtherValue"].Annotations)
+}
+
+// TestLoadOperationsWithMultipleOperationsAndFragments tests that documents with multiple
+// named operations are split into individual operations and that shared fragments are resolved
+func TestLoadOperationsWithMultipleOperationsAndFragments(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(tempDir, "fragments"), 0755))
+
+	testFiles := map[string]string{
+		"fragments/EmployeeFields.graphql": `fragment EmployeeFields on Employee {
+	id
+	name
+	...EmployeeContact
+}
+
+fragment EmployeeContact on Employee {
+	email
+}`,
+		"Employees.graphql": `# connect: cache=30s
+"""Finds an employee by ID"""
+query FindEmployee($id: ID!) {
+	employee(id: $id) {
+		...EmployeeFields
+	}
+}
+
+# connect: deprecated
+query ListEmployees {
+	employees {
+		...LocalFields
+	}
+}
+
+fragment LocalFields on Employee {
+	id
+}`,
+		"UnusedFragment.graphql": `query Single {
+	employees {
+		id
+	}
+}
+
+fragment Unused on Employee {
+	id
+}`,
+		"UnusedFragments.graphql": `query Plain {
+	employees {
+		id
+	}
+}
+
+query Other {
+	employees {
+		name
+	}
+}
+
+fragment Unused on Employee {
+	id
+}`,
+		"Anonymous.graphql": `{
+	employees {
+		id
+	}
+}
+
+query Named {
+	employees {
+		id
+	}
+}`,
+	}
+
+	for filename, content := range testFiles {
+		err := os.WriteFile(filepath.Join(tempDir, filename), []byte(content), 0644)
+		require.NoError(t, err, "Failed to write test file %s", filename)
+	}
+
+	schemaStr := `
+type Query {
+	employee(id: ID!): Employee
+	employees: [Employee!]!
+}
+
+type Employee {
+	id: ID!
+	name: String!
+	email: String!
+}
+`
+	schemaDoc, report := astparser.ParseGraphqlDocumentString(schemaStr)
+	require.False(t, report.HasErrors())
+
+	err := asttransform.MergeDefinitionWithBaseSchema(&schemaDoc)
+	require.NoError(t, err)
+
+	loader := NewOperationLoader(zap.NewNop(), &schemaDoc)
+	operations, err := loader.LoadOperationsFromDirectory(tempDir)
+	require.NoError(t, err)
+
+	// The file mixing an anonymous and a named operation and the files with an unused fragment should be skipped
+	require.Len(t, operations, 2)
+
+	findEmployee := operations[0]
+	assert.Equal(t, "FindEmployee", findEmployee.Name)
+	assert.Equal(t, "query", findEmployee.OperationType)
+	assert.Equal(t, "Finds an employee by ID", findEmployee.Description)
+	assert.Equal(t, map[string]string{"cache": "30s"}, findEmployee.Annotations)
+	assert.Len(t, findEmployee.Document.OperationDefinitions, 1)
+	assert.Contains(t, findEmployee.OperationString, "fragment EmployeeFields on Employee")
+	assert.Contains(t, findEmployee.OperationString, "fragment EmployeeContact on Employee")
+	assert.NotContains(t, findEmployee.OperationString, "ListEmployees")
+	assert.NotContains(t, findEmployee.OperationString, "LocalFields")
+
+	listEmployees := operations[1]
+	assert.Equal(t, "ListEmployees", listEmployees.Name)
+	assert.Equal(t, map[string]string{"deprecated": "true"}, listEmployees.Annotations)
+	assert.Len(t, listEmployees.Document.OperationDefinitions, 1)
+	assert.Contains(t, listEmployees.OperationString, "fragment LocalFields on Employee")
+	assert.NotContains(t, listEmployees.OperationString, "FindEmployee")
+	assert.NotContains(t, listEmployees.OperationString, "EmployeeFields")
+}
+
+// TestOperationDeprecation tests that the deprecation annotations are reported by the operation
+func TestOperationDeprecation(t *testing.T) {
+	tests := []struct {
+		name               string
+		annotations        map[string]string
+		expectedDeprecated bool
+		expectedSunset     string
+	}{
+		{
+			name:               "no annotations",
+			expectedDeprecated: false,
+		},
+		{
+			name:               "deprecated",
+			annotations:        map[string]string{AnnotationDeprecated: "true"},
+			expectedDeprecated: true,
+		},
+		{
+			name:               "explicitly not deprecated",
+			annotations:        map[string]string{AnnotationDeprecated: "false"},
+			expectedDeprecated: false,
+		},
+		{
+			name:               "sunset implies deprecated",
+			annotations:        map[string]string{AnnotationSunset: "2025-12-31"},
+			expectedDeprecated: true,
+			expectedSunset:     "2025-12-31",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := Operation{Annotations: tt.annotations}
+			assert.Equal(t, tt.expectedDeprecated, op.IsDeprecated())
+			assert.Equal(t, tt.expectedSunset, op.Sunset())
+		})
+	}
+}
+
+// TestLoadOperationsWithDeprecationAnnotations tests that operations with malformed deprecation annotations are skipped
+func TestLoadOperationsWithDeprecationAnnotations(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := map[string]string{
+		"SunsetDate.graphql":          "# connect: sunset=2025-12-31\nquery SunsetDate { validField }",
+		"SunsetRFC3339.graphql":       "# connect: sunset=2025-12-31T00:00:00Z\nquery SunsetRFC3339 { validField }",
+		"NotDeprecated.graphql":       "# connect: deprecated=false\nquery NotDeprecated { validField }",
+		"SunsetMissing.graphql":       "# connect: sunset\nquery SunsetMissing { validField }",
+		"SunsetInvalid.graphql":       "# connect: sunset=soon\nquery SunsetInvalid { validField }",
+		"DeprecatedYes.graphql":       "# connect: deprecated=yes\nquery DeprecatedYes { validField }",
+		"DeprecatedOne.graphql":       "# connect: deprecated=1\nquery DeprecatedOne { validField }",
+		"SunsetDateTime.graphql":      "# connect: sunset=2025-12-31T00:00:00\nquery SunsetDateTime { validField }",
+		"SunsetNotDeprecated.graphql": "# connect: deprecated=false sunset=2025-12-31\nquery SunsetNotDeprecated { validField }",
+		"SunsetDeprecated.graphql":    "# connect: deprecated sunset=2025-12-31\nquery SunsetDeprecated { validField }",
+	}
+
+	for filename, content := range testFiles {
+		err := os.WriteFile(filepath.Join(tempDir, filename), []byte(content), 0644)
+		require.NoError(t, err, "Failed to write test file %s", filename)
+	}
+
+	schemaStr := `type Query { validField: String }`
+	schemaDoc, report := astparser.ParseGraphqlDocumentString(schemaStr)
+	require.False(t, report.HasErrors())
+
+	err := asttransform.MergeDefinitionWithBaseSchema(&schemaDoc)
+	require.NoError(t, err)
+
+	loader := NewOperationLoader(zap.NewNop(), &schemaDoc)
+	operations, err := loader.LoadOperationsFromDirectory(tempDir)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(operations))
+	for _, op := range operations {
+		names = append(names, op.Name)
+	}
+	assert.ElementsMatch(t, []string{"SunsetDate", "SunsetRFC3339", "NotDeprecated", "SunsetDeprecated"}, names)
+}